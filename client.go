@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 // HandlerType is a ComfyUI API handler type.
@@ -46,11 +48,30 @@ type StatusType string
 const (
 	// StatusPending is a ComfyUI generation API status pending.
 	StatusPending StatusType = "pending"
+	// StatusRunning is a ComfyUI generation API status running.
+	StatusRunning StatusType = "running"
 	// StatusSuccess is a ComfyUI generation API status success.
 	StatusSuccess StatusType = "success"
+	// StatusFailed is a ComfyUI generation API status failed.
+	StatusFailed StatusType = "failed"
+	// StatusCancelled is a ComfyUI generation API status cancelled.
+	StatusCancelled StatusType = "cancelled"
+	// StatusTimeout is a ComfyUI generation API status timeout.
+	StatusTimeout StatusType = "timeout"
 	// TODO: Add more status types.
 )
 
+// IsTerminal reports whether s is a terminal status, i.e. one after which
+// WorkflowStatus is not expected to change. Unknown statuses are treated as
+// non-terminal.
+func (s StatusType) IsTerminal() bool {
+	switch s {
+	case StatusSuccess, StatusFailed, StatusCancelled, StatusTimeout:
+		return true
+	}
+	return false
+}
+
 // OutputURLs contains information about the ComfyUI generation API output URLs.
 type OutputURLs struct {
 	// GCP is a ComfyUI generation API GCP URL. It contains a GET-signed 7-day URL.
@@ -87,11 +108,37 @@ type Client struct {
 	APIToken string
 	// Client is an optional HTTP client. If nil, http.DefaultClient is used.
 	Client *http.Client
+	// RetryPolicy controls retries of transient HTTP failures (network
+	// errors, 5xx, 429) for StartWorkflow and WorkflowStatus. The zero
+	// value disables retries; NewClient sets it to DefaultRetryPolicy.
+	RetryPolicy RetryPolicy
+	// Logger, if set, is called for every outbound request and inbound
+	// response of StartWorkflow and WorkflowStatus (including the polls of
+	// WaitForWorkflow). The stream connections opened by StreamWorkflow are
+	// not logged. A nil Logger (the default) disables logging entirely.
+	Logger LogFunc
+	// LogBodies enables size-capped request/response body previews in log
+	// fields. It has no effect if Logger is nil.
+	LogBodies bool
+	// OnRequest are called, in order, on every outgoing *http.Request of
+	// StartWorkflow and WorkflowStatus before it is sent. A non-nil error
+	// aborts the request and is returned to the caller. StreamWorkflow's
+	// stream requests are not passed to them.
+	OnRequest []func(*http.Request) error
+	// OnResponse are called, in order, on every *http.Response received by
+	// StartWorkflow and WorkflowStatus before its body is read. A non-nil
+	// error aborts processing of that response and is returned to the
+	// caller. StreamWorkflow's stream responses are not passed to them.
+	OnResponse []func(*http.Response) error
 }
 
 // NewClient returns a new ComfyUI API client.
-func NewClient(baseURL string) *Client {
-	return &Client{BaseURL: baseURL}
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{BaseURL: baseURL, RetryPolicy: DefaultRetryPolicy}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
 }
 
 func client(c *Client) *http.Client {
@@ -101,28 +148,139 @@ func client(c *Client) *http.Client {
 	return http.DefaultClient
 }
 
-func do[T any](c *Client, req *http.Request, v *T) (*T, error) {
-	resp, err := client(c).Do(req)
-	if err != nil {
-		return nil, err
+// do sends the request built by newReq, retrying transient failures per
+// c.RetryPolicy, and decodes a successful response into v. newReq is called
+// again for every attempt so it must produce a fresh, unconsumed request.
+func do[T any](ctx context.Context, c *Client, newReq func() (*http.Request, error), v *T) (*T, error) {
+	policy := c.RetryPolicy
+	var deadline time.Time
+	if policy.MaxElapsedTime > 0 {
+		deadline = time.Now().Add(policy.MaxElapsedTime)
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode >= 400 {
-		details, _ := io.ReadAll(resp.Body)
-		return nil, &ClientError{Code: resp.StatusCode, Details: details}
-	} else if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
-		return nil, err
+	var lastErr error
+	attempt := 0
+	for {
+		req, err := newReq()
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range c.OnRequest {
+			if err := f(req); err != nil {
+				return nil, err
+			}
+		}
+		reqFields := map[string]any{
+			"method":  req.Method,
+			"url":     req.URL.String(),
+			"attempt": attempt + 1,
+			"headers": redactedHeaders(req.Header),
+		}
+		if c.LogBodies {
+			if body, ok := requestBodyPreview(req); ok {
+				reqFields["body"] = body
+			}
+		}
+		c.log(req.Context(), "request", reqFields)
+
+		start := time.Now()
+		resp, doErr := client(c).Do(req)
+		duration := time.Since(start)
+
+		var wait time.Duration
+		if doErr != nil {
+			c.log(req.Context(), "response", map[string]any{
+				"method":   req.Method,
+				"url":      req.URL.String(),
+				"attempt":  attempt + 1,
+				"duration": duration.String(),
+				"error":    doErr.Error(),
+			})
+			lastErr = doErr
+		} else {
+			for _, f := range c.OnResponse {
+				if err := f(resp); err != nil {
+					resp.Body.Close()
+					return nil, err
+				}
+			}
+
+			body, readErr := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			logFields := map[string]any{
+				"method":   req.Method,
+				"url":      req.URL.String(),
+				"attempt":  attempt + 1,
+				"duration": duration.String(),
+				"status":   resp.StatusCode,
+			}
+			if c.LogBodies {
+				logFields["body"] = bodyPreview(body)
+			}
+			c.log(req.Context(), "response", logFields)
+
+			if resp.StatusCode < 400 {
+				if readErr != nil {
+					return nil, readErr
+				}
+				if err := json.Unmarshal(body, v); err != nil {
+					return nil, err
+				}
+				return v, nil
+			}
+
+			ce := &ClientError{Code: resp.StatusCode, Details: body, Attempts: attempt + 1}
+			lastErr = ce
+			if !retryableStatus(resp.StatusCode) {
+				return nil, ce
+			}
+			wait = parseRetryAfter(resp.Header, time.Now())
+		}
+
+		if attempt >= policy.MaxRetries {
+			break
+		}
+		if backoff := policy.nextInterval(attempt); backoff > wait {
+			wait = backoff
+		}
+		if !deadline.IsZero() && time.Now().Add(wait).After(deadline) {
+			break
+		}
+		attempt++
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
 	}
-	return v, nil
+
+	if ce, ok := lastErr.(*ClientError); ok {
+		return nil, ce
+	}
+	return nil, &ClientError{Attempts: attempt + 1, Err: lastErr}
 }
 
+// ClientError is returned for API responses with status 400 or above, and for network
+// failures once retries (per Client.RetryPolicy) are exhausted.
 type ClientError struct {
 	Code    int
 	Details []byte
+	// Attempts is the number of attempts made before this error was returned.
+	Attempts int
+	// Err is the underlying transport error for network-level failures.
+	// It is nil for HTTP status errors, which carry Code and Details instead.
+	Err error
 }
 
 // Error implements the error interface.
-func (e *ClientError) Error() string { return string(e.Details) }
+func (e *ClientError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("comfyui: request failed after %d attempt(s): %v", e.Attempts, e.Err)
+	}
+	return string(e.Details)
+}
+
+// Unwrap allows errors.Is/errors.As to reach the underlying transport error.
+func (e *ClientError) Unwrap() error { return e.Err }
 
 // StartWorkflowRequest is a ComfyUI API start request.
 type StartWorkflowRequest struct {
@@ -155,27 +313,25 @@ func NewStartWorkflowRequest(workflow []byte, opts ...NewStartWorkflowOptionsFun
 
 // StartWorkflow starts a ComfyUI API workflow.
 func (c *Client) StartWorkflow(ctx context.Context, prompt *StartWorkflowRequest) (*Status, error) {
-	req, err := c.newRequest(ctx, withPath("payload"), withBodyJSON(prompt))
-	if err != nil {
-		return nil, err
-	}
-	return do(c, req, new(Status))
+	return do(ctx, c, func() (*http.Request, error) {
+		return c.newRequest(ctx, withPath("payload"), withBodyJSON(prompt))
+	}, new(Status))
 }
 
 func (c *Client) WorkflowStatus(ctx context.Context, id string) (*Status, error) {
-	req, err := c.newRequest(ctx, withPath("result", id))
-	if err != nil {
-		return nil, err
-	}
-	return do(c, req, new(Status))
+	return do(ctx, c, func() (*http.Request, error) {
+		return c.newRequest(ctx, withPath("result", id))
+	}, new(Status))
 }
 
 type newRequestOptions struct {
-	Method  string
-	BaseURL string
-	Path    string
-	Body    io.Reader
-	err     error
+	Method      string
+	BaseURL     string
+	Path        string
+	Body        io.Reader
+	Accept      string
+	LastEventID string
+	err         error
 }
 
 type newRequestOptionFunc func(*newRequestOptions)
@@ -194,8 +350,19 @@ func withBodyJSON(payload any) newRequestOptionFunc {
 	}
 }
 
+// withAccept overrides the default "application/json" Accept header.
+func withAccept(accept string) newRequestOptionFunc {
+	return func(o *newRequestOptions) { o.Accept = accept }
+}
+
+// withLastEventID sets the Last-Event-ID header used to resume an SSE stream.
+// It is a no-op if id is empty.
+func withLastEventID(id string) newRequestOptionFunc {
+	return func(o *newRequestOptions) { o.LastEventID = id }
+}
+
 func (c *Client) newRequest(ctx context.Context, opts ...newRequestOptionFunc) (*http.Request, error) {
-	o := newRequestOptions{Method: http.MethodGet, BaseURL: c.BaseURL, Path: c.BaseURL}
+	o := newRequestOptions{Method: http.MethodGet, BaseURL: c.BaseURL, Path: c.BaseURL, Accept: "application/json"}
 	for _, f := range opts {
 		f(&o)
 		if o.err != nil {
@@ -206,10 +373,13 @@ func (c *Client) newRequest(ctx context.Context, opts ...newRequestOptionFunc) (
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept", o.Accept)
 	req.Header.Set("Content-Type", "application/json")
 	if c.APIToken != "" {
 		req.Header.Set("Authorization", "Bearer "+c.APIToken)
 	}
+	if o.LastEventID != "" {
+		req.Header.Set("Last-Event-ID", o.LastEventID)
+	}
 	return req, nil
 }
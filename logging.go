@@ -0,0 +1,84 @@
+package comfyui
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+// maxLoggedBodyBytes caps how much of a request/response body is included in
+// log fields when LogBodies is enabled.
+const maxLoggedBodyBytes = 2048
+
+// LogFunc logs a single client event (e.g. "request", "response") with
+// structured fields. It is called by Client for every outbound request and
+// inbound response of StartWorkflow and WorkflowStatus; StreamWorkflow's
+// stream connections are not logged. A nil LogFunc (the zero value)
+// disables logging.
+type LogFunc func(ctx context.Context, event string, fields map[string]any)
+
+// SlogLogger adapts an *slog.Logger to LogFunc, logging each event at
+// slog.LevelInfo with the given fields as attributes.
+func SlogLogger(l *slog.Logger) LogFunc {
+	return func(ctx context.Context, event string, fields map[string]any) {
+		args := make([]any, 0, len(fields)*2)
+		for k, v := range fields {
+			args = append(args, k, v)
+		}
+		l.InfoContext(ctx, event, args...)
+	}
+}
+
+// redactedHeaders returns a copy of h with sensitive values masked, suitable
+// for logging.
+func redactedHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		if len(v) == 0 {
+			continue
+		}
+		if http.CanonicalHeaderKey(k) == "Authorization" {
+			out[k] = "REDACTED"
+			continue
+		}
+		out[k] = v[0]
+	}
+	return out
+}
+
+// bodyPreview truncates b to at most maxLoggedBodyBytes, for use in log
+// fields.
+func bodyPreview(b []byte) string {
+	if len(b) > maxLoggedBodyBytes {
+		return string(b[:maxLoggedBodyBytes]) + "...(truncated)"
+	}
+	return string(b)
+}
+
+// requestBodyPreview returns a size-capped preview of req's body, without
+// consuming it. It relies on req.GetBody, which http.NewRequestWithContext
+// sets automatically for the *bytes.Buffer bodies withBodyJSON produces; it
+// reports false for bodyless requests (e.g. GET).
+func requestBodyPreview(req *http.Request) (string, bool) {
+	if req.GetBody == nil {
+		return "", false
+	}
+	rc, err := req.GetBody()
+	if err != nil {
+		return "", false
+	}
+	defer rc.Close()
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		return "", false
+	}
+	return bodyPreview(b), true
+}
+
+func (c *Client) log(ctx context.Context, event string, fields map[string]any) {
+	if c.Logger == nil {
+		return
+	}
+	c.Logger(ctx, event, fields)
+}
@@ -0,0 +1,129 @@
+package comfyui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type loggedEvent struct {
+	event  string
+	fields map[string]any
+}
+
+func TestDo_LogsRequestAndResponseWithBodyPreviewsAndRedaction(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"abc","status":"success"}`)
+	}))
+	defer srv.Close()
+
+	var logs []loggedEvent
+	c := NewClient(srv.URL)
+	c.APIToken = "super-secret-token"
+	c.LogBodies = true
+	c.Logger = func(_ context.Context, event string, fields map[string]any) {
+		logs = append(logs, loggedEvent{event, fields})
+	}
+
+	_, err := c.StartWorkflow(context.Background(), NewStartWorkflowRequest([]byte(`{"foo":"bar"}`)))
+	if err != nil {
+		t.Fatalf("StartWorkflow: %v", err)
+	}
+
+	var reqEvent, respEvent *loggedEvent
+	for i := range logs {
+		switch logs[i].event {
+		case "request":
+			reqEvent = &logs[i]
+		case "response":
+			respEvent = &logs[i]
+		}
+	}
+	if reqEvent == nil || respEvent == nil {
+		t.Fatalf("logs = %+v, want both a request and response event", logs)
+	}
+
+	if reqEvent.fields["attempt"] != 1 {
+		t.Errorf("request attempt = %v, want 1", reqEvent.fields["attempt"])
+	}
+	reqBody, _ := reqEvent.fields["body"].(string)
+	if !strings.Contains(reqBody, "workflow_json") {
+		t.Errorf("request body preview = %q, want it to contain the posted workflow JSON", reqBody)
+	}
+
+	headers, ok := reqEvent.fields["headers"].(map[string]string)
+	if !ok {
+		t.Fatalf("request headers = %v, want map[string]string", reqEvent.fields["headers"])
+	}
+	if headers["Authorization"] != "REDACTED" {
+		t.Errorf("Authorization header = %q, want it redacted", headers["Authorization"])
+	}
+
+	respBody, _ := respEvent.fields["body"].(string)
+	if !strings.Contains(respBody, `"status":"success"`) {
+		t.Errorf("response body preview = %q, want it to contain the decoded response", respBody)
+	}
+	if respEvent.fields["status"] != http.StatusOK {
+		t.Errorf("response status = %v, want %d", respEvent.fields["status"], http.StatusOK)
+	}
+}
+
+func TestDo_NoLogsWithoutLogger(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"abc","status":"success"}`)
+	}))
+	defer srv.Close()
+
+	// Logger is nil (the zero value); Client.log must be a no-op and must not
+	// panic even with LogBodies set.
+	c := NewClient(srv.URL)
+	c.LogBodies = true
+	if _, err := c.WorkflowStatus(context.Background(), "abc"); err != nil {
+		t.Fatalf("WorkflowStatus: %v", err)
+	}
+}
+
+func TestDo_OnRequestErrorAbortsCall(t *testing.T) {
+	var serverHit bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverHit = true
+		fmt.Fprint(w, `{"id":"abc","status":"success"}`)
+	}))
+	defer srv.Close()
+
+	wantErr := errors.New("onrequest boom")
+	c := NewClient(srv.URL)
+	c.OnRequest = []func(*http.Request) error{
+		func(*http.Request) error { return wantErr },
+	}
+
+	_, err := c.WorkflowStatus(context.Background(), "abc")
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	if serverHit {
+		t.Fatal("OnRequest error must abort before the request reaches the server")
+	}
+}
+
+func TestDo_OnResponseErrorAbortsCall(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"abc","status":"success"}`)
+	}))
+	defer srv.Close()
+
+	wantErr := errors.New("onresponse boom")
+	c := NewClient(srv.URL)
+	c.OnResponse = []func(*http.Response) error{
+		func(*http.Response) error { return wantErr },
+	}
+
+	_, err := c.WorkflowStatus(context.Background(), "abc")
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+}
@@ -0,0 +1,106 @@
+package comfyui
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how Client retries transient HTTP failures (network
+// errors, 5xx, 429) using exponential backoff with jitter. The delay before
+// attempt n (0-indexed) is InitialInterval * Multiplier^n, capped at
+// MaxInterval and jittered by +/- interval*RandomizationFactor.
+type RetryPolicy struct {
+	// MaxElapsedTime bounds the total time spent retrying a single call,
+	// across all attempts. Zero means no time bound; MaxRetries still
+	// applies.
+	MaxElapsedTime time.Duration
+	// InitialInterval is the backoff delay before the first retry.
+	InitialInterval time.Duration
+	// Multiplier scales the interval after each attempt.
+	Multiplier float64
+	// RandomizationFactor jitters each interval by +/- this fraction,
+	// e.g. 0.5 means +/- 50%.
+	RandomizationFactor float64
+	// MaxInterval caps the computed backoff interval, before jitter.
+	MaxInterval time.Duration
+	// MaxRetries caps the number of retries. Zero disables retrying.
+	MaxRetries int
+}
+
+// DefaultRetryPolicy is the RetryPolicy used by NewClient.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxElapsedTime:      15 * time.Second,
+	InitialInterval:     500 * time.Millisecond,
+	Multiplier:          2,
+	RandomizationFactor: 0.5,
+	MaxInterval:         10 * time.Second,
+	MaxRetries:          5,
+}
+
+// Option configures a Client. See NewClient.
+type Option func(*Client)
+
+// WithRetryPolicy sets the retry policy used for transient HTTP failures.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) { c.RetryPolicy = p }
+}
+
+// NoRetry disables retries, restoring single-attempt behavior.
+func NoRetry() Option {
+	return WithRetryPolicy(RetryPolicy{})
+}
+
+// nextInterval returns the backoff delay before the given retry attempt
+// (0-indexed).
+func (p RetryPolicy) nextInterval(attempt int) time.Duration {
+	if p.InitialInterval <= 0 {
+		return 0
+	}
+	interval := float64(p.InitialInterval) * math.Pow(p.Multiplier, float64(attempt))
+	if p.MaxInterval > 0 && interval > float64(p.MaxInterval) {
+		interval = float64(p.MaxInterval)
+	}
+	if p.RandomizationFactor > 0 {
+		delta := interval * p.RandomizationFactor
+		interval += delta*2*rand.Float64() - delta
+	}
+	if interval < 0 {
+		interval = 0
+	}
+	return time.Duration(interval)
+}
+
+// retryableStatus reports whether an HTTP response with the given status
+// code should be retried: 5xx, 429, 408, and 425.
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+		return true
+	}
+	return code >= 500
+}
+
+// parseRetryAfter parses a Retry-After header (delta-seconds or HTTP-date
+// form) into a duration to wait, relative to now. It returns 0 if the header
+// is absent or unparseable.
+func parseRetryAfter(h http.Header, now time.Time) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
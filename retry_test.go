@@ -0,0 +1,235 @@
+package comfyui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_NextInterval_GrowthAndCap(t *testing.T) {
+	p := RetryPolicy{
+		InitialInterval: 100 * time.Millisecond,
+		Multiplier:      2,
+		MaxInterval:     300 * time.Millisecond,
+	}
+
+	want := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		300 * time.Millisecond, // would be 400ms uncapped
+		300 * time.Millisecond, // stays capped
+	}
+	for attempt, w := range want {
+		if got := p.nextInterval(attempt); got != w {
+			t.Errorf("nextInterval(%d) = %s, want %s", attempt, got, w)
+		}
+	}
+}
+
+func TestRetryPolicy_NextInterval_ZeroInitialIntervalDisablesBackoff(t *testing.T) {
+	p := RetryPolicy{Multiplier: 2, MaxInterval: time.Second}
+	if got := p.nextInterval(3); got != 0 {
+		t.Fatalf("nextInterval = %s, want 0 when InitialInterval is 0", got)
+	}
+}
+
+func TestRetryPolicy_NextInterval_JitterWithinBounds(t *testing.T) {
+	p := RetryPolicy{
+		InitialInterval:     100 * time.Millisecond,
+		Multiplier:          1, // isolate jitter from growth
+		RandomizationFactor: 0.5,
+		MaxInterval:         time.Second,
+	}
+	base := 100 * time.Millisecond
+	min := time.Duration(float64(base) * 0.5)
+	max := time.Duration(float64(base) * 1.5)
+
+	for i := 0; i < 200; i++ {
+		got := p.nextInterval(0)
+		if got < min || got > max {
+			t.Fatalf("nextInterval() = %s, want within [%s, %s]", got, min, max)
+		}
+	}
+}
+
+func TestRetryableStatus(t *testing.T) {
+	cases := []struct {
+		code int
+		want bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusBadRequest, false},
+		{http.StatusUnauthorized, false},
+		{http.StatusForbidden, false},
+		{http.StatusRequestTimeout, true},
+		{http.StatusTooEarly, true},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusBadGateway, true},
+		{http.StatusServiceUnavailable, true},
+		{599, true},
+	}
+	for _, c := range cases {
+		if got := retryableStatus(c.code); got != c.want {
+			t.Errorf("retryableStatus(%d) = %v, want %v", c.code, got, c.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("delta-seconds", func(t *testing.T) {
+		h := http.Header{}
+		h.Set("Retry-After", "30")
+		if got, want := parseRetryAfter(h, now), 30*time.Second; got != want {
+			t.Fatalf("parseRetryAfter() = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("http-date", func(t *testing.T) {
+		h := http.Header{}
+		h.Set("Retry-After", now.Add(45*time.Second).Format(http.TimeFormat))
+		got := parseRetryAfter(h, now)
+		if got < 44*time.Second || got > 45*time.Second {
+			t.Fatalf("parseRetryAfter() = %s, want ~45s", got)
+		}
+	})
+
+	t.Run("http-date-in-past", func(t *testing.T) {
+		h := http.Header{}
+		h.Set("Retry-After", now.Add(-time.Minute).Format(http.TimeFormat))
+		if got := parseRetryAfter(h, now); got != 0 {
+			t.Fatalf("parseRetryAfter() = %s, want 0 for a past date", got)
+		}
+	})
+
+	t.Run("negative-delta-seconds", func(t *testing.T) {
+		h := http.Header{}
+		h.Set("Retry-After", "-5")
+		if got := parseRetryAfter(h, now); got != 0 {
+			t.Fatalf("parseRetryAfter() = %s, want 0 for a negative delta", got)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		h := http.Header{}
+		h.Set("Retry-After", "not-a-valid-value")
+		if got := parseRetryAfter(h, now); got != 0 {
+			t.Fatalf("parseRetryAfter() = %s, want 0 for a malformed header", got)
+		}
+	})
+
+	t.Run("absent", func(t *testing.T) {
+		if got := parseRetryAfter(http.Header{}, now); got != 0 {
+			t.Fatalf("parseRetryAfter() = %s, want 0 when absent", got)
+		}
+	})
+}
+
+func TestDo_NoRetryMakesExactlyOneAttempt(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, NoRetry())
+	_, err := c.WorkflowStatus(context.Background(), "abc")
+	if err == nil {
+		t.Fatal("expected an error from a 500 response")
+	}
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1 with NoRetry()", attempts)
+	}
+
+	var ce *ClientError
+	if ok := asClientError(err, &ce); !ok {
+		t.Fatalf("err = %v, want *ClientError", err)
+	}
+	if ce.Attempts != 1 {
+		t.Fatalf("ClientError.Attempts = %d, want 1", ce.Attempts)
+	}
+}
+
+func TestDo_MaxRetriesZeroMakesExactlyOneAttempt(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	c.RetryPolicy = RetryPolicy{MaxRetries: 0}
+	_, err := c.WorkflowStatus(context.Background(), "abc")
+	if err == nil {
+		t.Fatal("expected an error from a 503 response")
+	}
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1 with MaxRetries: 0", attempts)
+	}
+}
+
+func TestDo_RetriesTransientFailuresAndSucceeds(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"id":"abc","status":"success"}`)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	c.RetryPolicy = RetryPolicy{
+		MaxRetries:      5,
+		InitialInterval: time.Millisecond,
+		Multiplier:      1,
+		MaxInterval:     time.Millisecond,
+	}
+	status, err := c.WorkflowStatus(context.Background(), "abc")
+	if err != nil {
+		t.Fatalf("WorkflowStatus: %v", err)
+	}
+	if status.ID != "abc" || status.Status != StatusSuccess {
+		t.Fatalf("status = %+v", status)
+	}
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestDo_NonRetryableStatusReturnsImmediately(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	c.RetryPolicy = RetryPolicy{MaxRetries: 5, InitialInterval: time.Millisecond, Multiplier: 1}
+	_, err := c.WorkflowStatus(context.Background(), "abc")
+	if err == nil {
+		t.Fatal("expected an error from a 403 response")
+	}
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1: a 403 must not be retried", attempts)
+	}
+}
+
+func asClientError(err error, target **ClientError) bool {
+	ce, ok := err.(*ClientError)
+	if !ok {
+		return false
+	}
+	*target = ce
+	return true
+}
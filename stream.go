@@ -0,0 +1,268 @@
+package comfyui
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// StatusEvent is a single event delivered by Client.StreamWorkflow.
+type StatusEvent struct {
+	// Status is the decoded workflow status, if the event payload parsed as one.
+	Status *Status
+	// Progress is the progress fraction in [0,1] reported by the server, if any.
+	Progress float64
+	// Raw is the raw JSON payload of the event, for fields not modeled by Status.
+	Raw json.RawMessage
+	// Err is set when streaming ends abnormally; the channel is closed
+	// immediately after this event.
+	Err error
+}
+
+// StreamWorkflow streams workflow progress for id over Server-Sent Events at
+// {BaseURL}/result/{id}/stream. The returned channel is closed when ctx is
+// canceled, the workflow reaches a terminal status, or streaming fails after
+// exhausting Client.RetryPolicy. Both the initial connection and every
+// subsequent reconnect (replaying from the last received event via
+// Last-Event-ID) go through the same retry/backoff policy as other
+// requests, and each disconnect spends one retry of that budget; a dial
+// failure surfaces as an error only once the budget is used up. If the server doesn't support streaming (404 or 501),
+// StreamWorkflow falls back to WaitForWorkflow-style polling so existing
+// deployments keep working.
+func (c *Client) StreamWorkflow(ctx context.Context, id string) (<-chan StatusEvent, error) {
+	policy := c.RetryPolicy
+	var deadline time.Time
+	if policy.MaxElapsedTime > 0 {
+		deadline = time.Now().Add(policy.MaxElapsedTime)
+	}
+
+	attempt := 0
+	resp, fellBack, err := c.dialWithRetry(ctx, id, "", &attempt, policy, deadline)
+	if err != nil {
+		return nil, err
+	}
+	if fellBack {
+		return c.pollAsStream(ctx, id), nil
+	}
+
+	events := make(chan StatusEvent)
+	go c.streamSSE(ctx, id, resp, attempt, policy, deadline, events)
+	return events, nil
+}
+
+// openStream issues the streaming request. It returns a nil response (and no
+// error) when the server reports it doesn't support streaming, signaling the
+// caller to fall back to polling.
+func (c *Client) openStream(ctx context.Context, id, lastEventID string) (*http.Response, error) {
+	req, err := c.newRequest(ctx, withPath("result", id, "stream"), withAccept("text/event-stream"), withLastEventID(lastEventID))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client(c).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusNotImplemented {
+		resp.Body.Close()
+		return nil, nil
+	}
+	if resp.StatusCode >= 400 {
+		details, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, &ClientError{Code: resp.StatusCode, Details: details}
+	}
+	return resp, nil
+}
+
+// streamSSE consumes resp and any subsequent reconnections, sending decoded
+// events to events until the stream ends, closing events on return. attempt,
+// policy, and deadline carry forward the retry budget already spent opening
+// resp (see StreamWorkflow), so a flaky connection can't reset its backoff
+// by disconnecting and reconnecting.
+func (c *Client) streamSSE(ctx context.Context, id string, resp *http.Response, attempt int, policy RetryPolicy, deadline time.Time, events chan<- StatusEvent) {
+	defer close(events)
+	lastEventID := ""
+	for {
+		done, reconnect := consumeSSE(ctx, resp, events, &lastEventID)
+		if done || !reconnect {
+			return
+		}
+
+		// A disconnect spends retry budget just like a failed dial.
+		wait := policy.nextInterval(attempt)
+		if attempt >= policy.MaxRetries || (!deadline.IsZero() && time.Now().Add(wait).After(deadline)) {
+			events <- StatusEvent{Err: fmt.Errorf("comfyui: stream disconnected after %d attempt(s)", attempt+1)}
+			return
+		}
+		attempt++
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		r, fellBack, err := c.dialWithRetry(ctx, id, lastEventID, &attempt, policy, deadline)
+		if err != nil {
+			events <- StatusEvent{Err: err}
+			return
+		}
+		if fellBack {
+			// The server stopped supporting streaming mid-flight; fall back
+			// to polling for the remainder.
+			pollInto(ctx, c, id, events)
+			return
+		}
+		resp = r
+	}
+}
+
+// dialWithRetry opens the stream for id, retrying transient failures (per
+// policy's backoff, capped by policy.MaxRetries and deadline) until it
+// succeeds, the budget is exhausted, or ctx is done. Non-retryable HTTP
+// errors (e.g. 401, 403) are returned immediately, matching do[T]. attempt
+// is advanced in place so a caller can share one running count across the
+// initial connection and any later reconnects. fellBack reports that the
+// server doesn't support streaming (404/501), signaling the caller to fall
+// back to polling.
+func (c *Client) dialWithRetry(ctx context.Context, id, lastEventID string, attempt *int, policy RetryPolicy, deadline time.Time) (resp *http.Response, fellBack bool, err error) {
+	for {
+		r, dialErr := c.openStream(ctx, id, lastEventID)
+		if dialErr == nil {
+			if r == nil {
+				return nil, true, nil
+			}
+			return r, false, nil
+		}
+		if ce, ok := dialErr.(*ClientError); ok && !retryableStatus(ce.Code) {
+			return nil, false, ce
+		}
+
+		if *attempt >= policy.MaxRetries {
+			return nil, false, fmt.Errorf("comfyui: stream disconnected after %d attempt(s): %w", *attempt+1, dialErr)
+		}
+		wait := policy.nextInterval(*attempt)
+		if !deadline.IsZero() && time.Now().Add(wait).After(deadline) {
+			return nil, false, fmt.Errorf("comfyui: stream disconnected: retry deadline exceeded: %w", dialErr)
+		}
+		*attempt++
+		select {
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// consumeSSE reads SSE frames from resp until it's exhausted, ctx is done, or
+// a terminal status is reached. done reports the stream is finished for
+// good; reconnect reports the caller should reopen the stream.
+func consumeSSE(ctx context.Context, resp *http.Response, events chan<- StatusEvent, lastEventID *string) (done, reconnect bool) {
+	defer resp.Body.Close()
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	var dataLines []string
+	var eventID string
+
+	flushTerminal := false
+	flush := func() (stop bool) {
+		if len(dataLines) == 0 {
+			return false
+		}
+		raw := json.RawMessage(strings.Join(dataLines, "\n"))
+		dataLines = nil
+		if eventID != "" {
+			*lastEventID = eventID
+		}
+		ev := StatusEvent{Raw: raw}
+		var status Status
+		if err := json.Unmarshal(raw, &status); err == nil {
+			ev.Status = &status
+		}
+		if p, ok := progressFrom(raw); ok {
+			ev.Progress = p
+		}
+		select {
+		case events <- ev:
+		case <-ctx.Done():
+			return true
+		}
+		return ev.Status != nil && ev.Status.Status.IsTerminal()
+	}
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return true, false
+		}
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if stop := flush(); stop {
+				flushTerminal = true
+			}
+		case strings.HasPrefix(line, "id:"):
+			eventID = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+		case strings.HasPrefix(line, "data:"):
+			dataLines = append(dataLines, strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		case strings.HasPrefix(line, "event:"):
+			// Event names aren't distinguished; every frame decodes as a StatusEvent.
+		}
+		if flushTerminal {
+			return true, false
+		}
+	}
+	if flush() {
+		return true, false
+	}
+	if ctx.Err() != nil {
+		return true, false
+	}
+	return false, true
+}
+
+// progressFrom extracts a top-level "progress" field from raw, if present.
+func progressFrom(raw json.RawMessage) (float64, bool) {
+	var v struct {
+		Progress *float64 `json:"progress"`
+	}
+	if err := json.Unmarshal(raw, &v); err != nil || v.Progress == nil {
+		return 0, false
+	}
+	return *v.Progress, true
+}
+
+// pollAsStream falls back to WaitForWorkflow-style polling, surfacing each
+// status change as a StatusEvent on a channel shaped like StreamWorkflow's.
+func (c *Client) pollAsStream(ctx context.Context, id string) <-chan StatusEvent {
+	events := make(chan StatusEvent)
+	go func() {
+		defer close(events)
+		pollInto(ctx, c, id, events)
+	}()
+	return events
+}
+
+// pollInto drives WaitForWorkflow, forwarding each status change to events.
+// It does not close events.
+func pollInto(ctx context.Context, c *Client, id string, events chan<- StatusEvent) {
+	_, err := c.WaitForWorkflow(ctx, id, WithOnStatus(func(s *Status) {
+		raw, _ := json.Marshal(s)
+		select {
+		case events <- StatusEvent{Status: s, Raw: raw}:
+		case <-ctx.Done():
+		}
+	}))
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+	if _, ok := err.(*WorkflowError); ok {
+		// Already represented by the terminal status delivered above.
+		return
+	}
+	events <- StatusEvent{Err: err}
+}
@@ -0,0 +1,158 @@
+package comfyui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConsumeSSE_ParsesDataAndDetectsTerminal(t *testing.T) {
+	body := "id: 1\ndata: {\"id\":\"abc\",\"status\":\"running\",\"progress\":0.5}\n\n" +
+		"data: {\"id\":\"abc\",\"status\":\"success\"}\n\n"
+	resp := &http.Response{Body: io.NopCloser(strings.NewReader(body))}
+
+	events := make(chan StatusEvent, 4)
+	lastEventID := ""
+	done, reconnect := consumeSSE(context.Background(), resp, events, &lastEventID)
+	close(events)
+
+	if !done || reconnect {
+		t.Fatalf("done=%v reconnect=%v, want done=true reconnect=false on terminal status", done, reconnect)
+	}
+	if lastEventID != "1" {
+		t.Fatalf("lastEventID = %q, want %q", lastEventID, "1")
+	}
+
+	var got []StatusEvent
+	for ev := range events {
+		got = append(got, ev)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d events, want 2", len(got))
+	}
+	if got[0].Status.Status != StatusRunning || got[0].Progress != 0.5 {
+		t.Fatalf("event[0] = %+v", got[0])
+	}
+	if got[1].Status.Status != StatusSuccess {
+		t.Fatalf("event[1] = %+v", got[1])
+	}
+}
+
+func TestConsumeSSE_ReconnectsOnCleanDisconnect(t *testing.T) {
+	body := "data: {\"id\":\"abc\",\"status\":\"running\"}\n\n"
+	resp := &http.Response{Body: io.NopCloser(strings.NewReader(body))}
+
+	events := make(chan StatusEvent, 1)
+	lastEventID := ""
+	done, reconnect := consumeSSE(context.Background(), resp, events, &lastEventID)
+	close(events)
+
+	if done || !reconnect {
+		t.Fatalf("done=%v reconnect=%v, want done=false reconnect=true on a non-terminal disconnect", done, reconnect)
+	}
+}
+
+// sseServer streams one "running" event per connection, then closes the
+// connection without ever reaching a terminal status, forcing the client to
+// reconnect until the test closes the server out from under it.
+func sseServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/result/abc/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "data: %s\n\n", `{"id":"abc","status":"running"}`)
+		if fl, ok := w.(http.Flusher); ok {
+			fl.Flush()
+		}
+	})
+	return httptest.NewServer(mux)
+}
+
+// TestStreamWorkflow_ReconnectAfterDialFailureDoesNotPanic is a regression
+// test: StreamWorkflow used to hand a nil *http.Response to consumeSSE when
+// a reconnect dial failed (e.g. connection refused), crashing the process
+// with a nil-pointer dereference. Here we force exactly that by closing the
+// server out from under an active stream, so the next reconnect attempt
+// dials an address nobody is listening on.
+func TestStreamWorkflow_ReconnectAfterDialFailureDoesNotPanic(t *testing.T) {
+	srv := sseServer()
+
+	c := NewClient(srv.URL)
+	c.RetryPolicy = RetryPolicy{
+		MaxRetries:      2,
+		InitialInterval: time.Millisecond,
+		Multiplier:      1,
+		MaxInterval:     time.Millisecond,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := c.StreamWorkflow(ctx, "abc")
+	if err != nil {
+		t.Fatalf("StreamWorkflow: %v", err)
+	}
+
+	first, ok := <-events
+	if !ok || first.Status == nil || first.Status.Status != StatusRunning {
+		t.Fatalf("first event = %+v, ok=%v", first, ok)
+	}
+
+	// Close the server so the reconnect dial fails like a connection-refused
+	// would in production; the fix must retry the dial, not pass a nil
+	// response to consumeSSE.
+	srv.Close()
+
+	sawTerminalErr := false
+	for ev := range events {
+		if ev.Err != nil {
+			sawTerminalErr = true
+		}
+	}
+	if !sawTerminalErr {
+		t.Fatal("expected a terminal error event once reconnect retries were exhausted")
+	}
+}
+
+func TestStreamSSE_HonorsMaxElapsedTime(t *testing.T) {
+	srv := sseServer()
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	c.RetryPolicy = RetryPolicy{
+		MaxRetries:      1000, // would retry far longer than MaxElapsedTime allows if unchecked
+		InitialInterval: 50 * time.Millisecond,
+		Multiplier:      1,
+		MaxInterval:     50 * time.Millisecond,
+		MaxElapsedTime:  150 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := c.StreamWorkflow(ctx, "abc")
+	if err != nil {
+		t.Fatalf("StreamWorkflow: %v", err)
+	}
+
+	start := time.Now()
+	var sawTerminalErr bool
+	for ev := range events {
+		if ev.Err != nil {
+			sawTerminalErr = true
+		}
+	}
+	elapsed := time.Since(start)
+
+	if !sawTerminalErr {
+		t.Fatal("expected a terminal error event once MaxElapsedTime was exceeded")
+	}
+	if elapsed > 2*time.Second {
+		t.Fatalf("stream ran for %s, want it bounded by MaxElapsedTime (150ms) rather than MaxRetries (1000)", elapsed)
+	}
+}
@@ -0,0 +1,137 @@
+package comfyui
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WorkflowError is returned by WaitForWorkflow and StartAndWait when a
+// workflow reaches a terminal, non-success status.
+type WorkflowError struct {
+	// ID is the workflow request ID.
+	ID string
+	// Status is the terminal status that caused this error.
+	Status StatusType
+	// Message is the Status.Message reported by the API.
+	Message string
+	// ComfyUIResponse is the raw Status.ComfyUIResponse reported by the API.
+	ComfyUIResponse []byte
+}
+
+// Error implements the error interface.
+func (e *WorkflowError) Error() string {
+	return fmt.Sprintf("comfyui: workflow %q ended with status %q: %s", e.ID, e.Status, e.Message)
+}
+
+// waitOptions holds WaitForWorkflow settings.
+type waitOptions struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+	Deadline        time.Duration
+	OnStatus        func(*Status)
+}
+
+// WaitOption configures WaitForWorkflow. See WithPollInterval,
+// WithMaxPollInterval, WithPollBackoff, WithWaitDeadline, and WithOnStatus.
+type WaitOption func(*waitOptions)
+
+// WithPollInterval sets the initial delay between WorkflowStatus polls.
+func WithPollInterval(d time.Duration) WaitOption {
+	return func(o *waitOptions) { o.InitialInterval = d }
+}
+
+// WithMaxPollInterval caps the delay between polls as it backs off.
+func WithMaxPollInterval(d time.Duration) WaitOption {
+	return func(o *waitOptions) { o.MaxInterval = d }
+}
+
+// WithPollBackoff sets the multiplier applied to the poll interval after
+// each non-terminal poll.
+func WithPollBackoff(multiplier float64) WaitOption {
+	return func(o *waitOptions) { o.Multiplier = multiplier }
+}
+
+// WithWaitDeadline bounds the total time WaitForWorkflow may run, independent
+// of ctx. Zero (the default) means no additional bound.
+func WithWaitDeadline(d time.Duration) WaitOption {
+	return func(o *waitOptions) { o.Deadline = d }
+}
+
+// WithOnStatus sets a callback invoked with the latest Status every time it
+// changes, including the final terminal status.
+func WithOnStatus(f func(*Status)) WaitOption {
+	return func(o *waitOptions) { o.OnStatus = f }
+}
+
+// WaitForWorkflow polls WorkflowStatus for id until it reaches a terminal
+// StatusType (see StatusType.IsTerminal), the deadline set by
+// WithWaitDeadline elapses, or ctx is done. It returns a *WorkflowError if
+// the workflow ends in a non-success terminal status.
+func (c *Client) WaitForWorkflow(ctx context.Context, id string, opts ...WaitOption) (*Status, error) {
+	o := waitOptions{
+		InitialInterval: time.Second,
+		MaxInterval:     10 * time.Second,
+		Multiplier:      1.5,
+	}
+	for _, f := range opts {
+		f(&o)
+	}
+
+	var deadline time.Time
+	if o.Deadline > 0 {
+		deadline = time.Now().Add(o.Deadline)
+	}
+
+	interval := o.InitialInterval
+	var lastStatus StatusType
+	for {
+		status, err := c.WorkflowStatus(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if status.Status != lastStatus {
+			lastStatus = status.Status
+			if o.OnStatus != nil {
+				o.OnStatus(status)
+			}
+		}
+		if status.Status.IsTerminal() {
+			if status.Status != StatusSuccess {
+				return status, &WorkflowError{
+					ID:              id,
+					Status:          status.Status,
+					Message:         status.Message,
+					ComfyUIResponse: status.ComfyUIResponse,
+				}
+			}
+			return status, nil
+		}
+
+		if !deadline.IsZero() && time.Now().Add(interval).After(deadline) {
+			return status, context.DeadlineExceeded
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		interval = time.Duration(float64(interval) * o.Multiplier)
+		if o.MaxInterval > 0 && interval > o.MaxInterval {
+			interval = o.MaxInterval
+		}
+	}
+}
+
+// StartAndWait starts prompt and waits for it to complete, combining
+// StartWorkflow and WaitForWorkflow.
+func (c *Client) StartAndWait(ctx context.Context, prompt *StartWorkflowRequest, opts ...WaitOption) (*Status, error) {
+	started, err := c.StartWorkflow(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+	return c.WaitForWorkflow(ctx, started.ID, opts...)
+}
@@ -0,0 +1,192 @@
+package comfyui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// scriptedStatusServer replies with the next status in statuses on each
+// GET /result/{id}, repeating the last one once the script is exhausted.
+func scriptedStatusServer(t *testing.T, statuses []StatusType) (*httptest.Server, func() int) {
+	t.Helper()
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := calls
+		calls++
+		s := statuses[len(statuses)-1]
+		if i < len(statuses) {
+			s = statuses[i]
+		}
+		status := Status{ID: "abc", Status: s}
+		if s == StatusFailed {
+			status.Message = "boom"
+			status.ComfyUIResponse = json.RawMessage(`{"detail":"boom"}`)
+		}
+		json.NewEncoder(w).Encode(status)
+	}))
+	return srv, func() int { return calls }
+}
+
+func TestWaitForWorkflow_PollsUntilTerminal(t *testing.T) {
+	srv, calls := scriptedStatusServer(t, []StatusType{StatusPending, StatusRunning, StatusSuccess})
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	status, err := c.WaitForWorkflow(context.Background(), "abc", WithPollInterval(time.Millisecond), WithMaxPollInterval(time.Millisecond))
+	if err != nil {
+		t.Fatalf("WaitForWorkflow: %v", err)
+	}
+	if status.Status != StatusSuccess {
+		t.Fatalf("status = %q, want %q", status.Status, StatusSuccess)
+	}
+	if got := calls(); got != 3 {
+		t.Fatalf("polled %d times, want 3", got)
+	}
+}
+
+func TestWaitForWorkflow_NonTerminalStatusIsNotReturned(t *testing.T) {
+	srv, _ := scriptedStatusServer(t, []StatusType{StatusPending, StatusPending, StatusSuccess})
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	status, err := c.WaitForWorkflow(context.Background(), "abc", WithPollInterval(time.Millisecond), WithMaxPollInterval(time.Millisecond))
+	if err != nil {
+		t.Fatalf("WaitForWorkflow: %v", err)
+	}
+	if status.Status != StatusSuccess {
+		t.Fatalf("status = %q, want %q", status.Status, StatusSuccess)
+	}
+}
+
+func TestWaitForWorkflow_FailureReturnsWorkflowError(t *testing.T) {
+	srv, _ := scriptedStatusServer(t, []StatusType{StatusPending, StatusFailed})
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	_, err := c.WaitForWorkflow(context.Background(), "abc", WithPollInterval(time.Millisecond), WithMaxPollInterval(time.Millisecond))
+	if err == nil {
+		t.Fatal("expected a WorkflowError for a failed terminal status")
+	}
+	we, ok := err.(*WorkflowError)
+	if !ok {
+		t.Fatalf("err = %T, want *WorkflowError", err)
+	}
+	if we.Status != StatusFailed || we.Message != "boom" {
+		t.Fatalf("WorkflowError = %+v", we)
+	}
+	if string(we.ComfyUIResponse) != `{"detail":"boom"}` {
+		t.Fatalf("ComfyUIResponse = %s", we.ComfyUIResponse)
+	}
+}
+
+func TestWaitForWorkflow_OnStatusFiresOnlyOnChange(t *testing.T) {
+	srv, _ := scriptedStatusServer(t, []StatusType{StatusPending, StatusPending, StatusRunning, StatusSuccess})
+	defer srv.Close()
+
+	var transitions []StatusType
+	c := NewClient(srv.URL)
+	_, err := c.WaitForWorkflow(context.Background(), "abc",
+		WithPollInterval(time.Millisecond), WithMaxPollInterval(time.Millisecond),
+		WithOnStatus(func(s *Status) { transitions = append(transitions, s.Status) }),
+	)
+	if err != nil {
+		t.Fatalf("WaitForWorkflow: %v", err)
+	}
+	want := []StatusType{StatusPending, StatusRunning, StatusSuccess}
+	if len(transitions) != len(want) {
+		t.Fatalf("transitions = %v, want %v", transitions, want)
+	}
+	for i, s := range want {
+		if transitions[i] != s {
+			t.Fatalf("transitions = %v, want %v", transitions, want)
+		}
+	}
+}
+
+func TestWaitForWorkflow_DeadlineExceeded(t *testing.T) {
+	// Never reaches a terminal status, forcing the deadline to fire.
+	srv, _ := scriptedStatusServer(t, []StatusType{StatusPending})
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	_, err := c.WaitForWorkflow(context.Background(), "abc",
+		WithPollInterval(20*time.Millisecond), WithMaxPollInterval(20*time.Millisecond),
+		WithWaitDeadline(30*time.Millisecond),
+	)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestWaitForWorkflow_PollBackoffGrowsAndCaps(t *testing.T) {
+	var times []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		times = append(times, time.Now())
+		s := StatusPending
+		if len(times) >= 5 {
+			s = StatusSuccess
+		}
+		fmt.Fprintf(w, `{"id":"abc","status":%q}`, s)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	_, err := c.WaitForWorkflow(context.Background(), "abc",
+		WithPollInterval(10*time.Millisecond),
+		WithPollBackoff(2),
+		WithMaxPollInterval(25*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("WaitForWorkflow: %v", err)
+	}
+	if len(times) != 5 {
+		t.Fatalf("polled %d times, want 5", len(times))
+	}
+
+	gaps := make([]time.Duration, 0, len(times)-1)
+	for i := 1; i < len(times); i++ {
+		gaps = append(gaps, times[i].Sub(times[i-1]))
+	}
+	// Gaps should grow (10ms -> 20ms -> capped at 25ms -> 25ms), not stay flat.
+	if gaps[0] >= gaps[1] {
+		t.Fatalf("gaps = %v, want poll interval to grow under WithPollBackoff(2)", gaps)
+	}
+	// MaxInterval caps growth: no gap should run away far past 25ms.
+	for _, g := range gaps {
+		if g > 60*time.Millisecond {
+			t.Fatalf("gaps = %v, want capped near MaxPollInterval (25ms)", gaps)
+		}
+	}
+}
+
+func TestStartAndWait(t *testing.T) {
+	var started bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/payload":
+			started = true
+			fmt.Fprint(w, `{"id":"abc","status":"pending"}`)
+		default:
+			fmt.Fprint(w, `{"id":"abc","status":"success"}`)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	status, err := c.StartAndWait(context.Background(), NewStartWorkflowRequest([]byte(`{}`)),
+		WithPollInterval(time.Millisecond), WithMaxPollInterval(time.Millisecond))
+	if err != nil {
+		t.Fatalf("StartAndWait: %v", err)
+	}
+	if !started {
+		t.Fatal("expected StartAndWait to call StartWorkflow")
+	}
+	if status.Status != StatusSuccess {
+		t.Fatalf("status = %q, want %q", status.Status, StatusSuccess)
+	}
+}
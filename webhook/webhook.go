@@ -0,0 +1,173 @@
+// Package webhook provides server-side support for receiving the webhook
+// callbacks described by comfyui.Webhook: signature verification, replay
+// protection, and decoding into a *comfyui.Status.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	comfyui "github.com/yarcat/ai-dock-comfyui-go"
+)
+
+const (
+	// SignatureHeader carries hex(HMAC-SHA256(secret, timestamp + "." + body)).
+	SignatureHeader = "X-ComfyUI-Signature"
+	// TimestampHeader carries the Unix timestamp (seconds) the payload was signed at.
+	TimestampHeader = "X-ComfyUI-Timestamp"
+
+	defaultMaxBodyBytes = 1 << 20 // 1 MiB
+	defaultMaxSkew      = 5 * time.Minute
+)
+
+var (
+	// ErrMissingSignature is returned when the signature or timestamp header is absent.
+	ErrMissingSignature = errors.New("webhook: missing signature or timestamp header")
+	// ErrInvalidSignature is returned when the computed and received signatures don't match.
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+	// ErrTimestampSkew is returned when the timestamp header is outside the allowed skew.
+	ErrTimestampSkew = errors.New("webhook: timestamp outside allowed skew")
+)
+
+// Option configures Handler and Middleware.
+type Option func(*options)
+
+type options struct {
+	maxBodyBytes int64
+	maxSkew      time.Duration
+}
+
+// WithMaxBodyBytes caps the size of the request body read. The default is 1 MiB.
+func WithMaxBodyBytes(n int64) Option {
+	return func(o *options) { o.maxBodyBytes = n }
+}
+
+// WithMaxSkew sets the maximum allowed difference between TimestampHeader and
+// the current time. The default is 5 minutes.
+func WithMaxSkew(d time.Duration) Option {
+	return func(o *options) { o.maxSkew = d }
+}
+
+// Handler returns an http.Handler that verifies the webhook signature,
+// decodes the body into a *comfyui.Status, and invokes cb. It responds 2xx
+// on success, 401 on verification failure, and 400/500 on decoding/handler
+// errors.
+func Handler(secret string, cb func(context.Context, *comfyui.Status) error, opts ...Option) http.Handler {
+	o := newOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, err := verifyAndDecode(r, secret, o)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if err := cb(r.Context(), status); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// Middleware returns middleware that verifies and decodes the webhook
+// payload, stashes the decoded *comfyui.Status in the request context (see
+// StatusFromContext), and calls next. Use this to compose with
+// http.ServeMux or chi routers instead of terminating the chain with
+// Handler.
+func Middleware(secret string, opts ...Option) func(http.Handler) http.Handler {
+	o := newOptions(opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			status, err := verifyAndDecode(r, secret, o)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(withStatus(r.Context(), status)))
+		})
+	}
+}
+
+type statusContextKey struct{}
+
+func withStatus(ctx context.Context, s *comfyui.Status) context.Context {
+	return context.WithValue(ctx, statusContextKey{}, s)
+}
+
+// StatusFromContext returns the *comfyui.Status decoded by Middleware, if any.
+func StatusFromContext(ctx context.Context) (*comfyui.Status, bool) {
+	s, ok := ctx.Value(statusContextKey{}).(*comfyui.Status)
+	return s, ok
+}
+
+func newOptions(opts []Option) options {
+	o := options{maxBodyBytes: defaultMaxBodyBytes, maxSkew: defaultMaxSkew}
+	for _, f := range opts {
+		f(&o)
+	}
+	return o
+}
+
+func verifyAndDecode(r *http.Request, secret string, o options) (*comfyui.Status, error) {
+	ts := r.Header.Get(TimestampHeader)
+	sig := r.Header.Get(SignatureHeader)
+	if ts == "" || sig == "" {
+		return nil, ErrMissingSignature
+	}
+	secs, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: invalid timestamp: %w", err)
+	}
+	sentAt := time.Unix(secs, 0)
+	if skew := time.Since(sentAt); skew > o.maxSkew || skew < -o.maxSkew {
+		return nil, ErrTimestampSkew
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, o.maxBodyBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("webhook: reading body: %w", err)
+	}
+	if int64(len(body)) > o.maxBodyBytes {
+		return nil, fmt.Errorf("webhook: body exceeds %d bytes", o.maxBodyBytes)
+	}
+
+	want := SignPayload(secret, sentAt, body)
+	if !hmac.Equal([]byte(want), []byte(sig)) {
+		return nil, ErrInvalidSignature
+	}
+
+	status := new(comfyui.Status)
+	if err := json.Unmarshal(body, status); err != nil {
+		return nil, fmt.Errorf("webhook: decoding body: %w", err)
+	}
+	return status, nil
+}
+
+// SignPayload computes the SignatureHeader value for body sent at ts:
+// hex(HMAC-SHA256(secret, "<unix-seconds>." + body)). Callers sending
+// webhooks should set TimestampHeader to strconv.FormatInt(ts.Unix(), 10)
+// and SignatureHeader to this value.
+func SignPayload(secret string, ts time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(ts.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrMissingSignature), errors.Is(err, ErrInvalidSignature), errors.Is(err, ErrTimestampSkew):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,170 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	comfyui "github.com/yarcat/ai-dock-comfyui-go"
+)
+
+func sign(t *testing.T, secret string, ts time.Time, body []byte) (string, string) {
+	t.Helper()
+	return strconv.FormatInt(ts.Unix(), 10), SignPayload(secret, ts, body)
+}
+
+// flipFirstByte mutates sig's first byte so the result never matches a valid
+// signature, regardless of what that byte happened to be.
+func flipFirstByte(t *testing.T, sig string) string {
+	t.Helper()
+	raw, err := hex.DecodeString(sig)
+	if err != nil || len(raw) == 0 {
+		t.Fatalf("sig %q is not valid hex", sig)
+	}
+	raw[0] ^= 0xff
+	return hex.EncodeToString(raw)
+}
+
+func postWebhook(t *testing.T, h http.Handler, secret string, ts time.Time, body []byte, tamperSig bool) *httptest.ResponseRecorder {
+	t.Helper()
+	tsHeader, sig := sign(t, secret, ts, body)
+	if tamperSig {
+		sig = flipFirstByte(t, sig)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+	req.Header.Set(TimestampHeader, tsHeader)
+	req.Header.Set(SignatureHeader, sig)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler_Success(t *testing.T) {
+	const secret = "s3cr3t"
+	body := []byte(`{"id":"abc","status":"success"}`)
+
+	var got *comfyui.Status
+	h := Handler(secret, func(_ context.Context, s *comfyui.Status) error {
+		got = s
+		return nil
+	})
+
+	rec := postWebhook(t, h, secret, time.Now(), body, false)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d; body = %s", rec.Code, http.StatusNoContent, rec.Body.String())
+	}
+	if got == nil || got.ID != "abc" || got.Status != comfyui.StatusSuccess {
+		t.Fatalf("callback got %+v", got)
+	}
+}
+
+func TestHandler_InvalidSignature(t *testing.T) {
+	const secret = "s3cr3t"
+	body := []byte(`{"id":"abc","status":"success"}`)
+
+	h := Handler(secret, func(context.Context, *comfyui.Status) error {
+		t.Fatal("callback must not be invoked for an invalid signature")
+		return nil
+	})
+
+	rec := postWebhook(t, h, secret, time.Now(), body, true)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestHandler_WrongSecret(t *testing.T) {
+	body := []byte(`{"id":"abc","status":"success"}`)
+	h := Handler("correct-secret", func(context.Context, *comfyui.Status) error {
+		t.Fatal("callback must not be invoked when the secret doesn't match")
+		return nil
+	})
+
+	rec := postWebhook(t, h, "wrong-secret", time.Now(), body, false)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestHandler_ExpiredTimestamp(t *testing.T) {
+	const secret = "s3cr3t"
+	body := []byte(`{"id":"abc","status":"success"}`)
+	h := Handler(secret, func(context.Context, *comfyui.Status) error {
+		t.Fatal("callback must not be invoked for a replayed/expired timestamp")
+		return nil
+	}, WithMaxSkew(5*time.Minute))
+
+	rec := postWebhook(t, h, secret, time.Now().Add(-10*time.Minute), body, false)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestHandler_MissingHeaders(t *testing.T) {
+	h := Handler("s3cr3t", func(context.Context, *comfyui.Status) error {
+		t.Fatal("callback must not be invoked without signature headers")
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader([]byte(`{}`)))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestHandler_CallbackError(t *testing.T) {
+	const secret = "s3cr3t"
+	body := []byte(`{"id":"abc","status":"failed"}`)
+	h := Handler(secret, func(context.Context, *comfyui.Status) error {
+		return errBoom
+	})
+
+	rec := postWebhook(t, h, secret, time.Now(), body, false)
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestMiddleware_StoresStatusInContext(t *testing.T) {
+	const secret = "s3cr3t"
+	body := []byte(`{"id":"abc","status":"success"}`)
+
+	var gotInNext bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, ok := StatusFromContext(r.Context())
+		gotInNext = ok && s.ID == "abc"
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rec := postWebhook(t, Middleware(secret)(next), secret, time.Now(), body, false)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if !gotInNext {
+		t.Fatal("expected Middleware to stash the decoded status in the request context")
+	}
+}
+
+func TestSignPayload_Deterministic(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+	body := []byte(`{"id":"abc"}`)
+	if a, b := SignPayload("secret", ts, body), SignPayload("secret", ts, body); a != b {
+		t.Fatalf("SignPayload is not deterministic: %q != %q", a, b)
+	}
+	if SignPayload("secret", ts, body) == SignPayload("other-secret", ts, body) {
+		t.Fatal("SignPayload must depend on the secret")
+	}
+}
+
+var errBoom = &testError{"boom"}
+
+type testError struct{ msg string }
+
+func (e *testError) Error() string { return e.msg }